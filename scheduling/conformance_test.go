@@ -0,0 +1,212 @@
+package scheduling_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/apple/foundationdb/bindings/go/src/fdb"
+
+	"github.com/guisaez/fdb_class_scheduler/scheduling"
+)
+
+// newRepo builds a fresh Repository seeded with classes, for use by a single
+// test. Both TestMemRepository and TestFDBRepository feed the same table of
+// cases through their own newRepo, so the two implementations are held to
+// identical behavior.
+type newRepo func(t *testing.T, classes []scheduling.Class) scheduling.Repository
+
+func TestMemRepository(t *testing.T) {
+	runConformanceTests(t, func(t *testing.T, classes []scheduling.Class) scheduling.Repository {
+		return scheduling.NewMemRepository(classes)
+	})
+}
+
+func TestFDBRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("requires a running FoundationDB cluster")
+	}
+
+	fdb.MustAPIVersion(730)
+	db := fdb.MustOpenDefault()
+
+	runConformanceTests(t, func(t *testing.T, classes []scheduling.Class) scheduling.Repository {
+		repo, err := scheduling.OpenFDBRepository(db, []string{"scheduling_test", t.Name()})
+		if err != nil {
+			t.Fatalf("OpenFDBRepository: %v", err)
+		}
+		if err := repo.SeedClasses(classes); err != nil {
+			t.Fatalf("SeedClasses: %v", err)
+		}
+		return repo
+	})
+}
+
+func runConformanceTests(t *testing.T, newRepo newRepo) {
+	ctx := context.Background()
+	const (
+		alice = scheduling.StudentID("alice")
+		bio   = scheduling.Class("bio 101")
+		chem  = scheduling.Class("chem 101")
+	)
+
+	t.Run("lists seeded classes as available", func(t *testing.T) {
+		repo := newRepo(t, []scheduling.Class{bio, chem})
+
+		classes, err := repo.ListAvailableClasses(ctx)
+		if err != nil {
+			t.Fatalf("ListAvailableClasses: %v", err)
+		}
+		assertClassSet(t, classes, bio, chem)
+	})
+
+	t.Run("signup enrolls a student", func(t *testing.T) {
+		repo := newRepo(t, []scheduling.Class{bio})
+
+		if err := repo.Signup(ctx, alice, bio); err != nil {
+			t.Fatalf("Signup: %v", err)
+		}
+
+		enrolled, err := repo.Enrollments(ctx, alice)
+		if err != nil {
+			t.Fatalf("Enrollments: %v", err)
+		}
+		if len(enrolled) != 1 || enrolled[0].Class != bio {
+			t.Fatalf("Enrollments = %v, want [{%s %s}]", enrolled, alice, bio)
+		}
+	})
+
+	t.Run("signup for an unknown class fails", func(t *testing.T) {
+		repo := newRepo(t, []scheduling.Class{bio})
+
+		err := repo.Signup(ctx, alice, scheduling.Class("does not exist"))
+		if !errors.Is(err, scheduling.ErrClassNotFound) {
+			t.Fatalf("Signup error = %v, want ErrClassNotFound", err)
+		}
+	})
+
+	t.Run("duplicate signup is rejected", func(t *testing.T) {
+		repo := newRepo(t, []scheduling.Class{bio})
+
+		if err := repo.Signup(ctx, alice, bio); err != nil {
+			t.Fatalf("first Signup: %v", err)
+		}
+		if err := repo.Signup(ctx, alice, bio); !errors.Is(err, scheduling.ErrAlreadyEnrolled) {
+			t.Fatalf("second Signup error = %v, want ErrAlreadyEnrolled", err)
+		}
+	})
+
+	t.Run("enrollment limit is enforced", func(t *testing.T) {
+		classes := make([]scheduling.Class, scheduling.MaxEnrollments+1)
+		for i := range classes {
+			classes[i] = scheduling.Class(string(rune('a' + i)))
+		}
+		repo := newRepo(t, classes)
+
+		for i := 0; i < scheduling.MaxEnrollments; i++ {
+			if err := repo.Signup(ctx, alice, classes[i]); err != nil {
+				t.Fatalf("Signup %d: %v", i, err)
+			}
+		}
+
+		err := repo.Signup(ctx, alice, classes[scheduling.MaxEnrollments])
+		if !errors.Is(err, scheduling.ErrEnrollmentLimit) {
+			t.Fatalf("Signup error = %v, want ErrEnrollmentLimit", err)
+		}
+	})
+
+	t.Run("drop frees the seat and clears the enrollment", func(t *testing.T) {
+		repo := newRepo(t, []scheduling.Class{bio})
+
+		if err := repo.Signup(ctx, alice, bio); err != nil {
+			t.Fatalf("Signup: %v", err)
+		}
+		if err := repo.Drop(ctx, alice, bio); err != nil {
+			t.Fatalf("Drop: %v", err)
+		}
+
+		enrolled, err := repo.Enrollments(ctx, alice)
+		if err != nil {
+			t.Fatalf("Enrollments: %v", err)
+		}
+		if len(enrolled) != 0 {
+			t.Fatalf("Enrollments = %v, want none", enrolled)
+		}
+	})
+
+	t.Run("drop without enrollment fails", func(t *testing.T) {
+		repo := newRepo(t, []scheduling.Class{bio})
+
+		err := repo.Drop(ctx, alice, bio)
+		if !errors.Is(err, scheduling.ErrNotEnrolled) {
+			t.Fatalf("Drop error = %v, want ErrNotEnrolled", err)
+		}
+	})
+
+	t.Run("switch moves the enrollment atomically", func(t *testing.T) {
+		repo := newRepo(t, []scheduling.Class{bio, chem})
+
+		if err := repo.Signup(ctx, alice, bio); err != nil {
+			t.Fatalf("Signup: %v", err)
+		}
+		if err := repo.Switch(ctx, alice, bio, chem); err != nil {
+			t.Fatalf("Switch: %v", err)
+		}
+
+		enrolled, err := repo.Enrollments(ctx, alice)
+		if err != nil {
+			t.Fatalf("Enrollments: %v", err)
+		}
+		if len(enrolled) != 1 || enrolled[0].Class != chem {
+			t.Fatalf("Enrollments = %v, want [{%s %s}]", enrolled, alice, chem)
+		}
+	})
+
+	t.Run("switch to an already-enrolled class leaves the original enrollment intact", func(t *testing.T) {
+		repo := newRepo(t, []scheduling.Class{bio, chem})
+
+		if err := repo.Signup(ctx, alice, bio); err != nil {
+			t.Fatalf("Signup: %v", err)
+		}
+		if err := repo.Signup(ctx, alice, chem); err != nil {
+			t.Fatalf("Signup: %v", err)
+		}
+
+		err := repo.Switch(ctx, alice, bio, chem)
+		if !errors.Is(err, scheduling.ErrAlreadyEnrolled) {
+			t.Fatalf("Switch error = %v, want ErrAlreadyEnrolled", err)
+		}
+
+		enrolled, err := repo.Enrollments(ctx, alice)
+		if err != nil {
+			t.Fatalf("Enrollments: %v", err)
+		}
+		assertClassSet(t, classesOf(enrolled), bio, chem)
+	})
+}
+
+func classesOf(enrollments []scheduling.Enrollment) []scheduling.Class {
+	classes := make([]scheduling.Class, len(enrollments))
+	for i, e := range enrollments {
+		classes[i] = e.Class
+	}
+	return classes
+}
+
+func assertClassSet(t *testing.T, got []scheduling.Class, want ...scheduling.Class) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	seen := make(map[scheduling.Class]bool, len(got))
+	for _, c := range got {
+		seen[c] = true
+	}
+	for _, c := range want {
+		if !seen[c] {
+			t.Fatalf("got %v, want %v (missing %s)", got, want, c)
+		}
+	}
+}
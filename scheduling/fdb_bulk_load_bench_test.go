@@ -0,0 +1,60 @@
+package scheduling
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apple/foundationdb/bindings/go/src/fdb"
+)
+
+// BenchmarkSeedClasses compares the naive single-transaction loader against
+// the sharded, concurrent one for class lists in the range a real deployment
+// (not just this tutorial's ~1,620 sample classes) might generate.
+func BenchmarkSeedClasses(b *testing.B) {
+	if testing.Short() {
+		b.Skip("requires a running FoundationDB cluster")
+	}
+
+	fdb.MustAPIVersion(730)
+	db := fdb.MustOpenDefault()
+
+	for _, n := range []int{10_000, 100_000} {
+		classes := syntheticClasses(n)
+
+		b.Run(fmt.Sprintf("single-txn/%d", n), func(b *testing.B) {
+			repo, err := OpenFDBRepository(db, []string{"scheduling_bench", "single", fmt.Sprint(n)})
+			if err != nil {
+				b.Fatalf("OpenFDBRepository: %v", err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := repo.seedClassesSingleTxn(classes); err != nil {
+					b.Fatalf("seedClassesSingleTxn: %v", err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("sharded/%d", n), func(b *testing.B) {
+			repo, err := OpenFDBRepository(db, []string{"scheduling_bench", "sharded", fmt.Sprint(n)})
+			if err != nil {
+				b.Fatalf("OpenFDBRepository: %v", err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := repo.SeedClassesConcurrent(classes, defaultSeedWorkers, defaultSeedBatchSize); err != nil {
+					b.Fatalf("SeedClassesConcurrent: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func syntheticClasses(n int) []Class {
+	classes := make([]Class, n)
+	for i := range classes {
+		classes[i] = Class(fmt.Sprintf("synthetic-class-%d", i))
+	}
+	return classes
+}
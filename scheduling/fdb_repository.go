@@ -0,0 +1,372 @@
+package scheduling
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/apple/foundationdb/bindings/go/src/fdb"
+	"github.com/apple/foundationdb/bindings/go/src/fdb/directory"
+	"github.com/apple/foundationdb/bindings/go/src/fdb/subspace"
+	"github.com/apple/foundationdb/bindings/go/src/fdb/tuple"
+)
+
+// defaultSeedWorkers and defaultSeedBatchSize are the worker count and batch
+// size SeedClasses uses; they keep each transaction well under FoundationDB's
+// 10 MB / 5 s transaction limits for any class list this tutorial generates.
+const (
+	defaultSeedWorkers   = 8
+	defaultSeedBatchSize = 500
+)
+
+// FDBRepository is the Repository implementation backed by FoundationDB. It
+// stores each class's remaining seat count under a "class" subspace and each
+// enrollment under an "attends" subspace, keyed by (studentID, class) so a
+// student's enrollments can be range-read directly.
+//
+// ctx is accepted on every method to satisfy the Repository interface, but
+// is otherwise unused: the FoundationDB Go bindings don't take a context.
+type FDBRepository struct {
+	db       fdb.Transactor
+	courseSS subspace.Subspace
+	attendSS subspace.Subspace
+}
+
+// OpenFDBRepository opens (creating if necessary) the directory at dirPath
+// and returns a Repository backed by it. t is typically an fdb.Database, so
+// that every Repository method gets Transact's automatic retry loop.
+func OpenFDBRepository(t fdb.Transactor, dirPath []string) (*FDBRepository, error) {
+	schedulingDir, err := directory.CreateOrOpen(t, dirPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FDBRepository{
+		db:       t,
+		courseSS: schedulingDir.Sub("class"),
+		attendSS: schedulingDir.Sub("attends"),
+	}, nil
+}
+
+// SeedClasses clears the repository's directory and opens each of classes at
+// SeatCapacity seats. It's not part of the Repository interface: seeding is
+// an operational concern of the FDB-backed store, not something every
+// Repository needs to support (MemRepository takes its classes as a
+// constructor argument instead).
+//
+// It shards the writes across defaultSeedWorkers goroutines in batches of
+// defaultSeedBatchSize; call SeedClassesConcurrent directly to tune those
+// for a particular class list size.
+func (r *FDBRepository) SeedClasses(classes []Class) error {
+	return r.SeedClassesConcurrent(classes, defaultSeedWorkers, defaultSeedBatchSize)
+}
+
+// SeedClassesConcurrent clears the repository's directory in its own
+// transaction, then shards classes across workers goroutines, each
+// committing its batches of at most batchSize writes as its own transaction.
+// This is the bulk-load pattern FoundationDB recommends once a class list is
+// too large to fit in a single transaction's 10 MB / 5 s limits; see
+// seedClassesSingleTxn (exercised only by this package's benchmark) for the
+// naive one-shot alternative it replaces.
+func (r *FDBRepository) SeedClassesConcurrent(classes []Class, workers, batchSize int) error {
+	if workers <= 0 {
+		workers = 1
+	}
+	if batchSize <= 0 {
+		batchSize = len(classes)
+	}
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+
+	_, err := r.db.Transact(func(tr fdb.Transaction) (any, error) {
+		tr.ClearRange(r.courseSS)
+		tr.ClearRange(r.attendSS)
+		return nil, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	batches := make(chan []Class)
+	go func() {
+		defer close(batches)
+		for i := 0; i < len(classes); i += batchSize {
+			end := i + batchSize
+			if end > len(classes) {
+				end = len(classes)
+			}
+			batches <- classes[i:end]
+		}
+	}()
+
+	var wg sync.WaitGroup
+	var fe firstError
+
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				if fe.get() != nil {
+					continue
+				}
+				if _, err := r.db.Transact(func(tr fdb.Transaction) (any, error) {
+					for _, c := range batch {
+						tr.Set(r.courseSS.Pack(tuple.Tuple{string(c)}), []byte(strconv.FormatInt(SeatCapacity, 10)))
+					}
+					return nil, nil
+				}); err != nil {
+					fe.set(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return fe.get()
+}
+
+// seedClassesSingleTxn is the naive one-shot loader SeedClassesConcurrent
+// replaced: every class is written in a single transaction, which is fine
+// for the few thousand classes this tutorial generates but breaks down once
+// the class list no longer fits FoundationDB's transaction size/duration
+// limits. Kept for this package's benchmark to compare against.
+func (r *FDBRepository) seedClassesSingleTxn(classes []Class) error {
+	_, err := r.db.Transact(func(tr fdb.Transaction) (any, error) {
+		tr.ClearRange(r.courseSS)
+		tr.ClearRange(r.attendSS)
+
+		for _, c := range classes {
+			tr.Set(r.courseSS.Pack(tuple.Tuple{string(c)}), []byte(strconv.FormatInt(SeatCapacity, 10)))
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// firstError records the first error reported to it by set, discarding any
+// that follow — the minimal "errgroup-style" primitive SeedClassesConcurrent
+// needs without taking on an external dependency for one field's worth of
+// synchronization.
+type firstError struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (e *firstError) set(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+func (e *firstError) get() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err
+}
+
+func (r *FDBRepository) ListAvailableClasses(ctx context.Context) ([]Class, error) {
+	res, err := r.db.ReadTransact(func(rtr fdb.ReadTransaction) (any, error) {
+		var classes []Class
+		ri := rtr.GetRange(r.courseSS, fdb.RangeOptions{}).Iterator()
+		for ri.Advance() {
+			kv := ri.MustGet()
+
+			tup, err := r.courseSS.Unpack(kv.Key)
+			if err != nil {
+				return nil, err
+			}
+
+			seats, err := strconv.ParseInt(string(kv.Value), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+
+			if seats > 0 {
+				classes = append(classes, Class(tup[0].(string)))
+			}
+		}
+		return classes, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.([]Class), nil
+}
+
+// ListAvailableClassesSnapshot is ListAvailableClasses, but reads the class
+// range through rtr.Snapshot() instead of rtr directly. A plain GetRange
+// would add every scanned class to the caller's read conflict set, which
+// forces a retry whenever any class's seat counter changes underneath it —
+// fine for an occasional listing, but a serious problem once students are
+// signing up concurrently, as every browsing student would conflict with
+// every enrolling one. The trade-off is that a snapshot read isn't
+// serializable with concurrent writes: a class whose last seat is taken a
+// moment ago may still show up as available. Callers that need the stronger
+// guarantee should use ListAvailableClasses instead.
+func (r *FDBRepository) ListAvailableClassesSnapshot(ctx context.Context) ([]Class, error) {
+	res, err := r.db.ReadTransact(func(rtr fdb.ReadTransaction) (any, error) {
+		var classes []Class
+		ri := rtr.Snapshot().GetRange(r.courseSS, fdb.RangeOptions{}).Iterator()
+		for ri.Advance() {
+			kv := ri.MustGet()
+
+			tup, err := r.courseSS.Unpack(kv.Key)
+			if err != nil {
+				return nil, err
+			}
+
+			seats, err := strconv.ParseInt(string(kv.Value), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+
+			if seats > 0 {
+				classes = append(classes, Class(tup[0].(string)))
+			}
+		}
+		return classes, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.([]Class), nil
+}
+
+func (r *FDBRepository) Signup(ctx context.Context, studentID StudentID, class Class) error {
+	_, err := r.db.Transact(func(tr fdb.Transaction) (any, error) {
+		return nil, r.signup(tr, studentID, class)
+	})
+	return err
+}
+
+// signup takes an fdb.Transactor rather than calling r.db directly so that
+// Switch can compose it with drop into one transaction.
+//
+// Every read below is taken through tr.Snapshot(), so none of them adds to
+// the transaction's read conflict set on its own — otherwise the enrollment
+// count scan over attendSS.Sub(studentID) and the duplicate-signup check
+// would conflict with unrelated writes far more often than the signup
+// actually needs to. The one read that does need to serialize against other
+// signups/drops for the same class — the seat counter — gets an explicit
+// AddReadConflictRange limited to exactly that key, so two students racing
+// for the last seat in a class still properly conflict and retry.
+//
+// AddReadConflictKey is just sugar for AddReadConflictRange over the
+// single-key range [classKey, classKey + 0x00) — no need to build that
+// range by hand.
+func (r *FDBRepository) signup(t fdb.Transactor, studentID StudentID, class Class) error {
+	classKey := r.courseSS.Pack(tuple.Tuple{string(class)})
+	attendKey := r.attendSS.Pack(tuple.Tuple{string(studentID), string(class)})
+
+	_, err := t.Transact(func(tr fdb.Transaction) (ret any, err error) {
+		snap := tr.Snapshot()
+
+		v := snap.Get(classKey).MustGet()
+		if v == nil {
+			return nil, ErrClassNotFound
+		}
+
+		seats, err := strconv.ParseInt(string(v), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if seats <= 0 {
+			return nil, ErrClassFull
+		}
+
+		enrolled := 0
+		ri := snap.GetRange(r.attendSS.Sub(string(studentID)), fdb.RangeOptions{}).Iterator()
+		for ri.Advance() {
+			ri.MustGet()
+			enrolled++
+		}
+		if enrolled >= MaxEnrollments {
+			return nil, ErrEnrollmentLimit
+		}
+
+		if snap.Get(attendKey).MustGet() != nil {
+			return nil, ErrAlreadyEnrolled
+		}
+
+		if err := tr.AddReadConflictKey(classKey); err != nil {
+			return nil, err
+		}
+
+		tr.Set(classKey, []byte(strconv.FormatInt(seats-1, 10)))
+		tr.Set(attendKey, []byte{})
+		return
+	})
+	return err
+}
+
+func (r *FDBRepository) Drop(ctx context.Context, studentID StudentID, class Class) error {
+	_, err := r.db.Transact(func(tr fdb.Transaction) (any, error) {
+		return nil, r.drop(tr, studentID, class)
+	})
+	return err
+}
+
+// drop takes an fdb.Transactor for the same reason signup does.
+func (r *FDBRepository) drop(t fdb.Transactor, studentID StudentID, class Class) error {
+	classKey := r.courseSS.Pack(tuple.Tuple{string(class)})
+	attendKey := r.attendSS.Pack(tuple.Tuple{string(studentID), string(class)})
+
+	_, err := t.Transact(func(tr fdb.Transaction) (ret any, err error) {
+		v := tr.Get(classKey).MustGet()
+		if v == nil {
+			return nil, ErrClassNotFound
+		}
+		if tr.Get(attendKey).MustGet() == nil {
+			return nil, ErrNotEnrolled
+		}
+
+		seats, err := strconv.ParseInt(string(v), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		tr.Set(classKey, []byte(strconv.FormatInt(seats+1, 10)))
+		tr.Clear(attendKey)
+		return
+	})
+	return err
+}
+
+func (r *FDBRepository) Switch(ctx context.Context, studentID StudentID, oldClass, newClass Class) error {
+	_, err := r.db.Transact(func(tr fdb.Transaction) (any, error) {
+		if err := r.drop(tr, studentID, oldClass); err != nil {
+			return nil, err
+		}
+		if err := r.signup(tr, studentID, newClass); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+	return err
+}
+
+func (r *FDBRepository) Enrollments(ctx context.Context, studentID StudentID) ([]Enrollment, error) {
+	res, err := r.db.ReadTransact(func(rtr fdb.ReadTransaction) (any, error) {
+		var out []Enrollment
+		ri := rtr.GetRange(r.attendSS.Sub(string(studentID)), fdb.RangeOptions{}).Iterator()
+		for ri.Advance() {
+			kv := ri.MustGet()
+
+			tup, err := r.attendSS.Unpack(kv.Key)
+			if err != nil {
+				return nil, err
+			}
+
+			out = append(out, Enrollment{Student: studentID, Class: Class(tup[1].(string))})
+		}
+		return out, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.([]Enrollment), nil
+}
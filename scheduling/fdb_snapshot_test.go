@@ -0,0 +1,97 @@
+package scheduling
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/apple/foundationdb/bindings/go/src/fdb"
+)
+
+// TestListersDoNotConflictWithSignups runs concurrent listers against
+// concurrent signups for the same contested class, and checks the trade-off
+// ListAvailableClassesSnapshot/signup are built around: the listers' own
+// conflict-retry counter stays at zero even while the class's seat counter
+// is being decremented underneath them, while the signups themselves still
+// serialize correctly on that seat counter (no class is ever oversold).
+func TestListersDoNotConflictWithSignups(t *testing.T) {
+	if testing.Short() {
+		t.Skip("requires a running FoundationDB cluster")
+	}
+
+	fdb.MustAPIVersion(730)
+	db := fdb.MustOpenDefault()
+	ctx := context.Background()
+
+	const class = Class("contested class")
+	repo, err := OpenFDBRepository(db, []string{"scheduling_test", t.Name()})
+	if err != nil {
+		t.Fatalf("OpenFDBRepository: %v", err)
+	}
+	if err := repo.SeedClasses([]Class{class}); err != nil {
+		t.Fatalf("SeedClasses: %v", err)
+	}
+
+	const (
+		listers  = 10
+		listRuns = 50
+		signups  = 2 * SeatCapacity
+	)
+
+	var (
+		wg            sync.WaitGroup
+		listerRetries int64
+	)
+
+	wg.Add(listers)
+	for i := 0; i < listers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < listRuns; j++ {
+				retries, _, err := transactWithRetries(db, func(tr fdb.Transaction) error {
+					ri := tr.Snapshot().GetRange(repo.courseSS, fdb.RangeOptions{}).Iterator()
+					for ri.Advance() {
+						ri.MustGet()
+					}
+					return nil
+				})
+				if err != nil {
+					t.Errorf("lister transaction: %v", err)
+				}
+				atomic.AddInt64(&listerRetries, int64(retries))
+			}
+		}()
+	}
+
+	var committedSignups int64
+	wg.Add(signups)
+	for i := 0; i < signups; i++ {
+		go func(idx int) {
+			defer wg.Done()
+			studentID := StudentID("student-" + strconv.Itoa(idx))
+			if err := repo.Signup(ctx, studentID, class); err == nil {
+				atomic.AddInt64(&committedSignups, 1)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	if listerRetries != 0 {
+		t.Errorf("listers retried %d times; snapshot reads should never conflict", listerRetries)
+	}
+
+	if committedSignups > SeatCapacity {
+		t.Fatalf("committed %d signups for a %d-seat class", committedSignups, SeatCapacity)
+	}
+
+	available, err := repo.ListAvailableClassesSnapshot(ctx)
+	if err != nil {
+		t.Fatalf("ListAvailableClassesSnapshot: %v", err)
+	}
+	if committedSignups < SeatCapacity && len(available) != 1 {
+		t.Fatalf("ListAvailableClassesSnapshot = %v, want [%q] with seats left", available, class)
+	}
+}
@@ -0,0 +1,123 @@
+package scheduling
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemRepository is an in-memory Repository. It exists so that scheduling
+// logic and the code that calls it can be unit tested quickly, without a
+// running FoundationDB cluster.
+type MemRepository struct {
+	mu     sync.Mutex
+	seats  map[Class]int
+	attend map[StudentID]map[Class]struct{}
+}
+
+// NewMemRepository returns a MemRepository with each of classes opened at
+// SeatCapacity seats.
+func NewMemRepository(classes []Class) *MemRepository {
+	seats := make(map[Class]int, len(classes))
+	for _, c := range classes {
+		seats[c] = SeatCapacity
+	}
+	return &MemRepository{
+		seats:  seats,
+		attend: make(map[StudentID]map[Class]struct{}),
+	}
+}
+
+func (r *MemRepository) ListAvailableClasses(ctx context.Context) ([]Class, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var classes []Class
+	for c, seats := range r.seats {
+		if seats > 0 {
+			classes = append(classes, c)
+		}
+	}
+	sort.Slice(classes, func(i, j int) bool { return classes[i] < classes[j] })
+	return classes, nil
+}
+
+func (r *MemRepository) Signup(ctx context.Context, studentID StudentID, class Class) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.signupLocked(studentID, class)
+}
+
+func (r *MemRepository) signupLocked(studentID StudentID, class Class) error {
+	seats, ok := r.seats[class]
+	if !ok {
+		return ErrClassNotFound
+	}
+	if seats <= 0 {
+		return ErrClassFull
+	}
+	if len(r.attend[studentID]) >= MaxEnrollments {
+		return ErrEnrollmentLimit
+	}
+	if _, ok := r.attend[studentID][class]; ok {
+		return ErrAlreadyEnrolled
+	}
+
+	r.seats[class] = seats - 1
+	if r.attend[studentID] == nil {
+		r.attend[studentID] = make(map[Class]struct{})
+	}
+	r.attend[studentID][class] = struct{}{}
+	return nil
+}
+
+func (r *MemRepository) Drop(ctx context.Context, studentID StudentID, class Class) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.dropLocked(studentID, class)
+}
+
+func (r *MemRepository) dropLocked(studentID StudentID, class Class) error {
+	if _, ok := r.seats[class]; !ok {
+		return ErrClassNotFound
+	}
+	if _, ok := r.attend[studentID][class]; !ok {
+		return ErrNotEnrolled
+	}
+
+	r.seats[class]++
+	delete(r.attend[studentID], class)
+	return nil
+}
+
+func (r *MemRepository) Switch(ctx context.Context, studentID StudentID, oldClass, newClass Class) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.dropLocked(studentID, oldClass); err != nil {
+		return err
+	}
+	if err := r.signupLocked(studentID, newClass); err != nil {
+		// Roll back the drop so a rejected switch leaves the student's
+		// enrollments exactly as they were before the call.
+		r.seats[oldClass]--
+		if r.attend[studentID] == nil {
+			r.attend[studentID] = make(map[Class]struct{})
+		}
+		r.attend[studentID][oldClass] = struct{}{}
+		return err
+	}
+	return nil
+}
+
+func (r *MemRepository) Enrollments(ctx context.Context, studentID StudentID) ([]Enrollment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var out []Enrollment
+	for c := range r.attend[studentID] {
+		out = append(out, Enrollment{Student: studentID, Class: c})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Class < out[j].Class })
+	return out, nil
+}
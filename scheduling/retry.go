@@ -0,0 +1,50 @@
+package scheduling
+
+import "github.com/apple/foundationdb/bindings/go/src/fdb"
+
+// transactWithRetries runs fn against a single transaction created from db,
+// retrying on fdb.Error via tr.OnError exactly as Transact would, but
+// counting how many retries were absorbed along the way instead of hiding
+// them. It's used anywhere callers care about retry counts themselves
+// (simulation bookkeeping, conflict-range tests) rather than just the
+// final result.
+func transactWithRetries(db fdb.Database, fn func(tr fdb.Transaction) error) (retries int, committed bool, err error) {
+	tr, err := db.CreateTransaction()
+	if err != nil {
+		return
+	}
+
+	for {
+		err = func() (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					if e, ok := r.(fdb.Error); ok {
+						err = e
+					} else {
+						panic(r)
+					}
+				}
+			}()
+			if ferr := fn(tr); ferr != nil {
+				return ferr
+			}
+			return tr.Commit().Get()
+		}()
+
+		if err == nil {
+			committed = true
+			return
+		}
+
+		fe, ok := err.(fdb.Error)
+		if !ok {
+			return
+		}
+
+		if onErr := tr.OnError(fe).Get(); onErr != nil {
+			err = onErr
+			return
+		}
+		retries++
+	}
+}
@@ -0,0 +1,62 @@
+// Package scheduling holds the class-scheduling domain model and the
+// Repository interface that decouples it from any particular storage
+// backend. Code written against Repository depends only on the types and
+// errors in this file; the fdb/tuple encoding details live entirely behind
+// FDBRepository.
+package scheduling
+
+import (
+	"context"
+	"errors"
+)
+
+// SeatCapacity is the number of seats a class opens with, and MaxEnrollments
+// is the number of classes a single student may be enrolled in at once.
+const (
+	SeatCapacity   = 100
+	MaxEnrollments = 5
+)
+
+// StudentID identifies a student independent of any storage representation.
+type StudentID string
+
+// Class identifies a class by name.
+type Class string
+
+// Enrollment is a (student, class) pairing returned by Repository.Enrollments.
+type Enrollment struct {
+	Student StudentID
+	Class   Class
+}
+
+// Errors returned by Repository implementations. Callers can compare against
+// these with errors.Is regardless of which implementation is in use.
+var (
+	ErrClassNotFound   = errors.New("scheduling: class not found")
+	ErrClassFull       = errors.New("scheduling: class is full")
+	ErrAlreadyEnrolled = errors.New("scheduling: student already enrolled in class")
+	ErrEnrollmentLimit = errors.New("scheduling: student has reached the enrollment limit")
+	ErrNotEnrolled     = errors.New("scheduling: student not enrolled in class")
+)
+
+// Repository is the storage boundary for the scheduling domain. It exposes
+// only domain types and errors, so application code can be written and
+// tested against it without ever importing fdb or tuple.
+type Repository interface {
+	// ListAvailableClasses returns the classes that still have open seats.
+	ListAvailableClasses(ctx context.Context) ([]Class, error)
+
+	// Signup enrolls studentID in class, subject to SeatCapacity and
+	// MaxEnrollments.
+	Signup(ctx context.Context, studentID StudentID, class Class) error
+
+	// Drop un-enrolls studentID from class, freeing a seat.
+	Drop(ctx context.Context, studentID StudentID, class Class) error
+
+	// Switch moves studentID from oldClass to newClass as a single atomic
+	// operation: either both the drop and the signup apply, or neither does.
+	Switch(ctx context.Context, studentID StudentID, oldClass, newClass Class) error
+
+	// Enrollments lists the classes studentID is currently enrolled in.
+	Enrollments(ctx context.Context, studentID StudentID) ([]Enrollment, error)
+}
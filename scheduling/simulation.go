@@ -0,0 +1,176 @@
+package scheduling
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"strconv"
+	"sync"
+
+	"github.com/apple/foundationdb/bindings/go/src/fdb"
+)
+
+// simResult tallies what a single simulated student did: how many of each
+// operation it attempted, how many transactions committed, and how many
+// fdb.Error retries those transactions needed along the way.
+type simResult struct {
+	signups, drops, switches int
+	committed, retries       int
+}
+
+// RunSimulation opens an FDBRepository over the "scheduling" directory in db
+// and spawns one goroutine per simulated student, each repeatedly signing
+// up, dropping, or switching against the shared pool of classes, using
+// sync.WaitGroup to wait for everyone to finish. Every goroutine tracks its
+// own enrolled classes locally and samples a fresh ListAvailableClasses
+// snapshot before every operation, so the targets it picks stay realistic as
+// seats fill up under concurrent load.
+//
+// This is a stress test for the contention FDBRepository's signup/drop are
+// built to handle: many goroutines racing to decrement the same seat
+// counters, retried by transactWithRetries, which mirrors Transact's retry
+// loop but counts the retries. It logs
+// per-operation counts and the number of fdb.Error retries absorbed, then
+// verifies that the database is left in a consistent state.
+func RunSimulation(db fdb.Database, students, opsPerStudent int) error {
+	r, err := OpenFDBRepository(db, []string{"scheduling"})
+	if err != nil {
+		return err
+	}
+
+	results := make([]simResult, students)
+
+	var wg sync.WaitGroup
+	wg.Add(students)
+
+	for s := 0; s < students; s++ {
+		go func(idx int) {
+			defer wg.Done()
+
+			studentID := StudentID(fmt.Sprintf("student-%d", idx))
+			rng := rand.New(rand.NewSource(int64(idx)))
+
+			var enrolled []Class
+			var res simResult
+
+			for op := 0; op < opsPerStudent; op++ {
+				available, err := r.ListAvailableClasses(context.Background())
+				if err != nil || len(available) == 0 {
+					continue
+				}
+
+				var fn func(tr fdb.Transaction) error
+				switch {
+				case len(enrolled) == 0 || rng.Intn(3) == 0:
+					// Signup: pick any open class. It may be one we're already
+					// in, in which case the signup is rejected as a duplicate.
+					class := available[rng.Intn(len(available))]
+					fn = func(tr fdb.Transaction) error { return r.signup(tr, studentID, class) }
+					res.signups++
+					enrolled = append(enrolled, class)
+				case rng.Intn(2) == 0:
+					// Drop one of our current classes.
+					i := rng.Intn(len(enrolled))
+					class := enrolled[i]
+					fn = func(tr fdb.Transaction) error { return r.drop(tr, studentID, class) }
+					res.drops++
+					enrolled = append(enrolled[:i], enrolled[i+1:]...)
+				default:
+					// Switch one of our current classes for an open one.
+					i := rng.Intn(len(enrolled))
+					oldClass := enrolled[i]
+					newClass := available[rng.Intn(len(available))]
+					fn = func(tr fdb.Transaction) error {
+						if err := r.drop(tr, studentID, oldClass); err != nil {
+							return err
+						}
+						return r.signup(tr, studentID, newClass)
+					}
+					res.switches++
+					enrolled[i] = newClass
+				}
+
+				retries, _, err := transactWithRetries(db, fn)
+				res.retries += retries
+				if err != nil {
+					// Rejected (full class, duplicate signup, etc.) rather than
+					// retried away. Our local `enrolled` bookkeeping is only ever
+					// used to pick realistic next targets, and ListAvailableClasses
+					// resamples fresh every iteration, so we just move on.
+					continue
+				}
+				res.committed++
+			}
+
+			results[idx] = res
+		}(s)
+	}
+
+	wg.Wait()
+
+	var totalSignups, totalDrops, totalSwitches, totalCommitted, totalRetries int
+	for _, res := range results {
+		totalSignups += res.signups
+		totalDrops += res.drops
+		totalSwitches += res.switches
+		totalCommitted += res.committed
+		totalRetries += res.retries
+	}
+
+	log.Printf("simulation: signups=%d drops=%d switches=%d committed=%d retries=%d",
+		totalSignups, totalDrops, totalSwitches, totalCommitted, totalRetries)
+
+	return verifySimulationInvariants(r)
+}
+
+// verifySimulationInvariants checks that the database is left in a
+// consistent state after a simulation run: every attendSS entry refers to a
+// class that still exists in courseSS, and every class's seat counter
+// equals SeatCapacity minus the number of students currently enrolled in it.
+func verifySimulationInvariants(r *FDBRepository) error {
+	_, err := r.db.ReadTransact(func(rtr fdb.ReadTransaction) (any, error) {
+		seats := make(map[string]int64)
+		ri := rtr.GetRange(r.courseSS, fdb.RangeOptions{}).Iterator()
+		for ri.Advance() {
+			kv := ri.MustGet()
+
+			tup, err := r.courseSS.Unpack(kv.Key)
+			if err != nil {
+				return nil, err
+			}
+
+			n, err := strconv.ParseInt(string(kv.Value), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			seats[tup[0].(string)] = n
+		}
+
+		enrollees := make(map[string]int64)
+		ai := rtr.GetRange(r.attendSS, fdb.RangeOptions{}).Iterator()
+		for ai.Advance() {
+			kv := ai.MustGet()
+
+			tup, err := r.attendSS.Unpack(kv.Key)
+			if err != nil {
+				return nil, err
+			}
+
+			class := tup[1].(string)
+			if _, ok := seats[class]; !ok {
+				return nil, fmt.Errorf("attend record for class %q with no matching course", class)
+			}
+			enrollees[class]++
+		}
+
+		for class, remaining := range seats {
+			if remaining != SeatCapacity-enrollees[class] {
+				return nil, fmt.Errorf("class %q has %d seats but %d enrollees (want %d)",
+					class, remaining, enrollees[class], SeatCapacity-enrollees[class])
+			}
+		}
+		return nil, nil
+	})
+	return err
+}